@@ -0,0 +1,171 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+	defaultDrainGracePeriod = 90 * time.Second
+	defaultDrainTimeout     = 3 * time.Minute
+	drainPollInterval       = 2 * time.Second
+)
+
+// NodeDrainer cordons a node and evicts its pods, mirroring the behavior of
+// `kubectl drain` closely enough for e2e teardown: it respects
+// PodDisruptionBudgets through the eviction API and leaves DaemonSet-owned
+// and mirror pods alone since they are not meaningfully evictable.
+type NodeDrainer struct {
+	K8s                kubernetes.Interface
+	GracePeriodSeconds int64
+	Timeout            time.Duration
+}
+
+// NewNodeDrainer returns a NodeDrainer with the given client and repo-default
+// grace period and timeout.
+func NewNodeDrainer(k8s kubernetes.Interface) *NodeDrainer {
+	return &NodeDrainer{
+		K8s:                k8s,
+		GracePeriodSeconds: int64(defaultDrainGracePeriod.Seconds()),
+		Timeout:            defaultDrainTimeout,
+	}
+}
+
+// Cordon marks the node unschedulable.
+func (d *NodeDrainer) Cordon(ctx context.Context, nodeName string) error {
+	node, err := d.K8s.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("getting node %s: %w", nodeName, err)
+	}
+
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = true
+	if _, err := d.K8s.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("cordoning node %s: %w", nodeName, err)
+	}
+	return nil
+}
+
+// Drain cordons the node and evicts every evictable pod running on it,
+// waiting for each eviction to be acknowledged before returning.
+func (d *NodeDrainer) Drain(ctx context.Context, nodeName string, logger logr.Logger) error {
+	if err := d.Cordon(ctx, nodeName); err != nil {
+		return err
+	}
+
+	pods, err := d.podsToEvict(ctx, nodeName)
+	if err != nil {
+		return fmt.Errorf("listing pods on node %s: %w", nodeName, err)
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+
+	for _, pod := range pods {
+		logger.Info("Evicting pod", "pod", pod.Name, "namespace", pod.Namespace)
+		if err := d.evict(drainCtx, pod); err != nil {
+			return fmt.Errorf("evicting pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// podsToEvict returns the pods scheduled on nodeName that kubectl drain would
+// evict: it skips pods already completed, DaemonSet-owned pods, and mirror
+// (static) pods, none of which are meaningfully evictable via the API.
+func (d *NodeDrainer) podsToEvict(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	podList, err := d.K8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if isMirrorPod(pod) || isDaemonSetPod(pod) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+func (d *NodeDrainer) evict(ctx context.Context, pod corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &metav1.DeleteOptions{
+			GracePeriodSeconds: &d.GracePeriodSeconds,
+		},
+	}
+
+	// A PodDisruptionBudget that would be violated by this eviction makes
+	// the API return 429 Too Many Requests rather than reject the request
+	// outright; kubectl drain retries in that case until the budget allows
+	// it or the node's drain timeout elapses, so we do the same here.
+	err := wait.PollUntilContextCancel(ctx, drainPollInterval, true, func(ctx context.Context) (bool, error) {
+		err := d.K8s.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if apierrors.IsTooManyRequests(err) {
+			return false, nil
+		}
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("evicting pod, possibly blocked by a PodDisruptionBudget: %w", err)
+	}
+
+	return wait.PollUntilContextCancel(ctx, drainPollInterval, true, func(ctx context.Context) (bool, error) {
+		_, err := d.K8s.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return false, nil
+	})
+}
+
+func isDaemonSetPod(pod corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+func isMirrorPod(pod corev1.Pod) bool {
+	_, ok := pod.Annotations[mirrorPodAnnotation]
+	return ok
+}
@@ -0,0 +1,130 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNodeDrainerPodsToEvict(t *testing.T) {
+	const nodeName = "test-node"
+
+	regular := newTestPod("regular-pod", nodeName, nil, corev1.PodRunning)
+	daemonset := newTestPod("daemonset-pod", nodeName, []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds"}}, corev1.PodRunning)
+	mirror := newTestPod("mirror-pod", nodeName, nil, corev1.PodRunning)
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "abc123"}
+	completed := newTestPod("completed-pod", nodeName, nil, corev1.PodSucceeded)
+
+	d := &NodeDrainer{K8s: fake.NewSimpleClientset(&regular, &daemonset, &mirror, &completed)}
+
+	got, err := d.podsToEvict(context.Background(), nodeName)
+	if err != nil {
+		t.Fatalf("podsToEvict returned error: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Name != regular.Name {
+		t.Fatalf("expected only %q to be evictable, got %v", regular.Name, podNames(got))
+	}
+}
+
+func TestNodeDrainerEvictRetriesOnPDBBlock(t *testing.T) {
+	pod := newTestPod("blocked-pod", "test-node", nil, corev1.PodRunning)
+
+	var calls int
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if calls == 1 {
+			return true, nil, apierrors.NewTooManyRequests("blocked by a PodDisruptionBudget", 0)
+		}
+		return true, nil, nil
+	})
+
+	d := &NodeDrainer{K8s: clientset, GracePeriodSeconds: 30}
+
+	if err := d.evict(context.Background(), pod); err != nil {
+		t.Fatalf("evict returned error: %v", err)
+	}
+	if calls < 2 {
+		t.Fatalf("expected evict to retry after a 429, got %d call(s)", calls)
+	}
+}
+
+func TestNodeDrainerEvictFailsImmediatelyOnOtherError(t *testing.T) {
+	pod := newTestPod("unevictable-pod", "test-node", nil, corev1.PodRunning)
+
+	var calls int
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, pod.Name, fmt.Errorf("denied by admission webhook"))
+	})
+
+	d := &NodeDrainer{K8s: clientset, GracePeriodSeconds: 30}
+
+	if err := d.evict(context.Background(), pod); err == nil {
+		t.Fatal("expected evict to return an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected evict not to retry a non-429 error, got %d call(s)", calls)
+	}
+}
+
+func TestIsDaemonSetPod(t *testing.T) {
+	pod := newTestPod("ds-pod", "node", []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds"}}, corev1.PodRunning)
+	if !isDaemonSetPod(pod) {
+		t.Fatal("expected pod owned by a DaemonSet to be reported as such")
+	}
+
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs"}}
+	if isDaemonSetPod(pod) {
+		t.Fatal("expected pod owned by a ReplicaSet not to be reported as a DaemonSet pod")
+	}
+}
+
+func TestIsMirrorPod(t *testing.T) {
+	pod := newTestPod("mirror-pod", "node", nil, corev1.PodRunning)
+	if isMirrorPod(pod) {
+		t.Fatal("expected pod without the mirror annotation not to be reported as one")
+	}
+
+	pod.Annotations = map[string]string{mirrorPodAnnotation: "abc123"}
+	if !isMirrorPod(pod) {
+		t.Fatal("expected pod with the mirror annotation to be reported as one")
+	}
+}
+
+func podNames(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func newTestPod(name, nodeName string, owners []metav1.OwnerReference, phase corev1.PodPhase) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			OwnerReferences: owners,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
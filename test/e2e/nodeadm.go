@@ -5,25 +5,29 @@ package e2e
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	_ "embed"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/eks-hybrid/internal/api"
 	"github.com/aws/eks-hybrid/internal/creds"
 	"github.com/go-logr/logr"
 	"github.com/tredoe/osutil/user/crypt"
 	"github.com/tredoe/osutil/user/crypt/sha512_crypt"
+	"golang.org/x/crypto/ssh"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -36,10 +40,16 @@ type UserDataInput struct {
 	CredsProviderName string
 	KubernetesVersion string
 	NodeadmUrls       NodeadmURLs
-	NodeadmConfigYaml string
-	Provider          string
-	RootPasswordHash  string
-	Files             []File
+	// NodeadmInstallCommands are the shell lines that fetch and place the
+	// nodeadm binary at /tmp/nodeadm, resolved ahead of time from the
+	// NodeadmSource the test harness was configured with. OSes that support
+	// it should prefer this over building a curl command from NodeadmUrls
+	// directly, since it also covers non-S3 distribution modes.
+	NodeadmInstallCommands []string
+	NodeadmConfigYaml      string
+	Provider               string
+	RootPasswordHash       string
+	Files                  []File
 }
 
 type HybridNode struct {
@@ -54,7 +64,7 @@ type File struct {
 // NodeadmOS defines an interface for operating system-specific behavior.
 type NodeadmOS interface {
 	Name() string
-	AMIName(ctx context.Context, awsSession *session.Session) (string, error)
+	AMIName(ctx context.Context, awsConfig aws.Config) (string, error)
 	BuildUserData(UserDataInput UserDataInput) ([]byte, error)
 	InstanceType() string
 }
@@ -66,10 +76,11 @@ type NodeadmCredentialsProvider interface {
 	VerifyUninstall(ctx context.Context, instanceId string) error
 	InstanceID(node HybridNode) string
 	FilesForNode() ([]File, error)
+	RemoteExecutor(node HybridNode) RemoteCommandExecutor
 }
 
 type SsmProvider struct {
-	ssmClient *ssm.SSM
+	ssmClient *ssm.Client
 	role      string
 }
 
@@ -123,12 +134,23 @@ func (s *SsmProvider) FilesForNode() ([]File, error) {
 	return nil, nil
 }
 
+func (s *SsmProvider) RemoteExecutor(node HybridNode) RemoteCommandExecutor {
+	return &ssmExecutor{
+		client:     s.ssmClient,
+		instanceID: s.InstanceID(node),
+	}
+}
+
 type IamRolesAnywhereProvider struct {
 	nodeName       string
 	trustAnchorARN string
 	profileARN     string
 	roleARN        string
 	ca             *certificate
+
+	sshKeyOnce sync.Once
+	sshKey     *sshKeyPair
+	sshKeyErr  error
 }
 
 func (i *IamRolesAnywhereProvider) Name() creds.CredentialProvider {
@@ -175,6 +197,12 @@ func (i *IamRolesAnywhereProvider) FilesForNode() ([]File, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	key, err := i.keyPair()
+	if err != nil {
+		return nil, fmt.Errorf("generating ssh keypair for node: %w", err)
+	}
+
 	return []File{
 		{
 			Content: string(nodeCertificate.CertPEM),
@@ -184,9 +212,38 @@ func (i *IamRolesAnywhereProvider) FilesForNode() ([]File, error) {
 			Content: string(nodeCertificate.KeyPEM),
 			Path:    "/etc/iam/pki/server.key",
 		},
+		{
+			// Goes through the same write_files cloud-init mechanism as
+			// the certificate above, so the node accepts the private key
+			// RemoteExecutor's sshExecutor dials with.
+			Content: key.authorizedKey,
+			Path:    sshAuthorizedKeysPath,
+		},
 	}, nil
 }
 
+// keyPair lazily generates the SSH keypair used to reach this node over SSH,
+// caching it so the public key injected via FilesForNode matches the private
+// key the remote executor dials with.
+func (i *IamRolesAnywhereProvider) keyPair() (*sshKeyPair, error) {
+	i.sshKeyOnce.Do(func() {
+		i.sshKey, i.sshKeyErr = generateSSHKeyPair()
+	})
+	return i.sshKey, i.sshKeyErr
+}
+
+func (i *IamRolesAnywhereProvider) RemoteExecutor(node HybridNode) RemoteCommandExecutor {
+	key, err := i.keyPair()
+	if err != nil {
+		return &erroredExecutor{err: fmt.Errorf("generating ssh keypair for node: %w", err)}
+	}
+
+	return &sshExecutor{
+		host:   nodeInternalIP(node.node),
+		signer: key.signer,
+	}
+}
+
 func parseS3URL(s3URL string) (bucket, key string, err error) {
 	parsedURL, err := url.Parse(s3URL)
 	if err != nil {
@@ -199,54 +256,50 @@ func parseS3URL(s3URL string) (bucket, key string, err error) {
 	return bucket, key, nil
 }
 
-func generatePreSignedURL(client *s3.S3, bucket, key string, expiration time.Duration) (string, error) {
-	req, _ := client.GetObjectRequest(&s3.GetObjectInput{
+func generatePreSignedURL(ctx context.Context, presignClient *s3.PresignClient, bucket, key string, expiration time.Duration) (string, error) {
+	req, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
-	})
-
-	url, err := req.Presign(expiration)
+	}, s3.WithPresignExpires(expiration))
 	if err != nil {
 		return "", fmt.Errorf("generating pre-signed URL: %v", err)
 	}
-	return url, nil
+	return req.URL, nil
 }
 
-func getNodeadmURL(client *s3.S3, nodeadmUrl string) (string, error) {
+func getNodeadmURL(ctx context.Context, presignClient *s3.PresignClient, nodeadmUrl string) (string, error) {
 	s3Bucket, s3BucketKey, err := parseS3URL(nodeadmUrl)
 	if err != nil {
 		return "", fmt.Errorf("parsing S3 URL: %v", err)
 	}
 
-	preSignedURL, err := generatePreSignedURL(client, s3Bucket, s3BucketKey, 30*time.Minute)
+	preSignedURL, err := generatePreSignedURL(ctx, presignClient, s3Bucket, s3BucketKey, 30*time.Minute)
 	if err != nil {
 		return "", fmt.Errorf("getting presigned URL for nodeadm: %v", err)
 	}
 	return preSignedURL, nil
 }
 
-func runNodeadmUninstall(ctx context.Context, client *ssm.SSM, instanceID string, logger logr.Logger) error {
+func runNodeadmUninstall(ctx context.Context, executor RemoteCommandExecutor, k8s kubernetes.Interface, nodeName string, logger logr.Logger) error {
+	if err := NewNodeDrainer(k8s).Drain(ctx, nodeName, logger); err != nil {
+		return fmt.Errorf("draining node %s: %w", nodeName, err)
+	}
+
 	commands := []string{
-		// TODO: @pjshah run uninstall without node-validation and pod-validation flags after adding cordon and drain node functionality
 		"set -eux",
-		"sudo /tmp/nodeadm uninstall -skip node-validation,pod-validation",
+		"sudo /tmp/nodeadm uninstall",
 		"sudo cloud-init clean --logs",
 		"sudo rm -rf /var/lib/cloud/instances",
 	}
-	ssmConfig := &ssmConfig{
-		client:     client,
-		instanceID: instanceID,
-		commands:   commands,
-	}
-	// TODO: handle provider specific ssm command wait status
-	outputs, err := ssmConfig.runCommandsOnInstanceWaitForInProgress(ctx, logger)
+	// TODO: handle provider specific command wait status
+	outputs, err := executor.RunCommands(ctx, commands)
 	if err != nil {
-		return fmt.Errorf("running SSM command: %w", err)
+		return fmt.Errorf("running nodeadm uninstall: %w", err)
 	}
 	logger.Info("Nodeadm Uninstall", "output", outputs)
 	for _, output := range outputs {
 		if *output.Status != "Success" && *output.Status != "InProgress" {
-			return fmt.Errorf("node uninstall SSM command did not properly reach InProgress")
+			return fmt.Errorf("node uninstall command did not properly reach InProgress")
 		}
 	}
 	return nil
@@ -258,7 +311,11 @@ func generateOSPassword() (string, string, error) {
 	const length = 8
 	password := make([]byte, length)
 	for i := range password {
-		password[i] = letters[rand.Intn(len(letters))]
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(letters))))
+		if err != nil {
+			return "", "", fmt.Errorf("generating root password: %s", err)
+		}
+		password[i] = letters[n.Int64()]
 	}
 	c := crypt.New(crypt.SHA512)
 	s := sha512_crypt.GetSalt()
@@ -269,3 +326,34 @@ func generateOSPassword() (string, string, error) {
 	}
 	return string(password), string(hash), nil
 }
+
+// sshKeyPair is the per-test SSH keypair used to authenticate with nodes that
+// don't run the SSM agent.
+type sshKeyPair struct {
+	signer        ssh.Signer
+	authorizedKey string
+}
+
+// generateSSHKeyPair generates an ed25519 keypair and returns it alongside
+// its OpenSSH authorized_keys line.
+func generateSSHKeyPair() (*sshKeyPair, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating ed25519 keypair: %w", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("creating ssh signer: %w", err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("creating ssh public key: %w", err)
+	}
+
+	return &sshKeyPair{
+		signer:        signer,
+		authorizedKey: string(ssh.MarshalAuthorizedKey(sshPub)),
+	}, nil
+}
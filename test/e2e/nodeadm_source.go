@@ -0,0 +1,106 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Distribution modes for NodeadmSource.
+const (
+	NodeadmSourceS3  = "s3"
+	NodeadmSourceECR = "ecr"
+)
+
+// NodeadmSource resolves the shell steps cloud-init runs to place the
+// nodeadm binary at /tmp/nodeadm on an instance, independent of how it is
+// actually distributed (S3 object vs. OCI artifact in ECR).
+type NodeadmSource interface {
+	Name() string
+	InstallCommands(ctx context.Context, arch string) ([]string, error)
+}
+
+// S3PresignedSource is the original distribution mode: nodeadm is an S3
+// object and a presigned URL is baked into user-data for cloud-init to curl.
+type S3PresignedSource struct {
+	Presign *s3.PresignClient
+	URLs    NodeadmURLs
+}
+
+func (s *S3PresignedSource) Name() string {
+	return NodeadmSourceS3
+}
+
+func (s *S3PresignedSource) InstallCommands(ctx context.Context, arch string) ([]string, error) {
+	nodeadmURL, err := urlForArch(s.URLs, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	presignedURL, err := getNodeadmURL(ctx, s.Presign, nodeadmURL)
+	if err != nil {
+		return nil, fmt.Errorf("resolving nodeadm s3 source: %w", err)
+	}
+
+	return []string{
+		fmt.Sprintf("sudo curl --retry 5 -L -o /tmp/nodeadm %q", presignedURL),
+		"sudo chmod +x /tmp/nodeadm",
+	}, nil
+}
+
+// ECRArtifactSource distributes nodeadm as an OCI artifact in ECR, the same
+// way a customer would ship nodeadm alongside container images rather than
+// as a raw S3 object. Instances authenticate to ECR with the standard
+// credential helper flow and pull the artifact with oras.
+type ECRArtifactSource struct {
+	Registry   string // e.g. 012345678901.dkr.ecr.us-west-2.amazonaws.com
+	Repository string // e.g. eks-hybrid/nodeadm
+	Region     string
+	Tags       NodeadmURLs // AMD/ARM hold the per-architecture image tag
+}
+
+func (e *ECRArtifactSource) Name() string {
+	return NodeadmSourceECR
+}
+
+func (e *ECRArtifactSource) InstallCommands(ctx context.Context, arch string) ([]string, error) {
+	tag, err := urlForArch(e.Tags, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := fmt.Sprintf("%s/%s:%s", e.Registry, e.Repository, tag)
+	return []string{
+		fmt.Sprintf("aws ecr get-login-password --region %s | sudo oras login %s --username AWS --password-stdin", e.Region, e.Registry),
+		fmt.Sprintf("sudo oras pull %s -o /tmp", ref),
+		"sudo chmod +x /tmp/nodeadm",
+	}, nil
+}
+
+func urlForArch(urls NodeadmURLs, arch string) (string, error) {
+	switch arch {
+	case amd64Arch:
+		return urls.AMD, nil
+	case arm64Arch:
+		return urls.ARM, nil
+	default:
+		return "", fmt.Errorf("unsupported architecture %q for nodeadm source", arch)
+	}
+}
+
+// NewNodeadmSource builds the NodeadmSource for the given kind. An empty kind
+// defaults to the original S3 mode.
+func NewNodeadmSource(kind string, presign *s3.PresignClient, s3URLs NodeadmURLs, ecrRegistry, ecrRepository, ecrRegion string, ecrTags NodeadmURLs) (NodeadmSource, error) {
+	switch kind {
+	case "", NodeadmSourceS3:
+		return &S3PresignedSource{Presign: presign, URLs: s3URLs}, nil
+	case NodeadmSourceECR:
+		return &ECRArtifactSource{Registry: ecrRegistry, Repository: ecrRepository, Region: ecrRegion, Tags: ecrTags}, nil
+	default:
+		return nil, fmt.Errorf("unknown nodeadm source %q", kind)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUrlForArch(t *testing.T) {
+	urls := NodeadmURLs{AMD: "amd-value", ARM: "arm-value"}
+
+	got, err := urlForArch(urls, amd64Arch)
+	if err != nil || got != urls.AMD {
+		t.Fatalf("urlForArch(%q) = %q, %v; want %q, nil", amd64Arch, got, err, urls.AMD)
+	}
+
+	got, err = urlForArch(urls, arm64Arch)
+	if err != nil || got != urls.ARM {
+		t.Fatalf("urlForArch(%q) = %q, %v; want %q, nil", arm64Arch, got, err, urls.ARM)
+	}
+
+	if _, err := urlForArch(urls, "mips"); err == nil {
+		t.Fatal("expected an error for an unsupported architecture")
+	}
+}
+
+func TestNewNodeadmSource(t *testing.T) {
+	s3URLs := NodeadmURLs{AMD: "s3://bucket/amd", ARM: "s3://bucket/arm"}
+	ecrTags := NodeadmURLs{AMD: "amd-tag", ARM: "arm-tag"}
+
+	for _, kind := range []string{"", NodeadmSourceS3} {
+		source, err := NewNodeadmSource(kind, nil, s3URLs, "", "", "", ecrTags)
+		if err != nil {
+			t.Fatalf("NewNodeadmSource(%q) returned error: %v", kind, err)
+		}
+		if _, ok := source.(*S3PresignedSource); !ok {
+			t.Fatalf("NewNodeadmSource(%q) = %T, want *S3PresignedSource", kind, source)
+		}
+		if source.Name() != NodeadmSourceS3 {
+			t.Fatalf("Name() = %q, want %q", source.Name(), NodeadmSourceS3)
+		}
+	}
+
+	source, err := NewNodeadmSource(NodeadmSourceECR, nil, s3URLs, "012345678901.dkr.ecr.us-west-2.amazonaws.com", "eks-hybrid/nodeadm", "us-west-2", ecrTags)
+	if err != nil {
+		t.Fatalf("NewNodeadmSource(ecr) returned error: %v", err)
+	}
+	ecrSource, ok := source.(*ECRArtifactSource)
+	if !ok {
+		t.Fatalf("NewNodeadmSource(ecr) = %T, want *ECRArtifactSource", source)
+	}
+	if ecrSource.Region != "us-west-2" {
+		t.Fatalf("Region = %q, want %q", ecrSource.Region, "us-west-2")
+	}
+
+	if _, err := NewNodeadmSource("bogus", nil, s3URLs, "", "", "", ecrTags); err == nil {
+		t.Fatal("expected an error for an unknown nodeadm source kind")
+	}
+}
+
+func TestECRArtifactSourceInstallCommands(t *testing.T) {
+	source := &ECRArtifactSource{
+		Registry:   "012345678901.dkr.ecr.us-west-2.amazonaws.com",
+		Repository: "eks-hybrid/nodeadm",
+		Region:     "us-west-2",
+		Tags:       NodeadmURLs{AMD: "amd-tag", ARM: "arm-tag"},
+	}
+
+	commands, err := source.InstallCommands(context.Background(), amd64Arch)
+	if err != nil {
+		t.Fatalf("InstallCommands returned error: %v", err)
+	}
+
+	if len(commands) != 3 {
+		t.Fatalf("expected 3 install commands, got %d: %v", len(commands), commands)
+	}
+}
@@ -0,0 +1,149 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	sshUser               = "ec2-user"
+	sshAuthorizedKeysPath = "/home/" + sshUser + "/.ssh/authorized_keys"
+	sshDialTimeout        = 30 * time.Second
+)
+
+// RemoteCommandExecutor runs shell commands against a node, abstracting over
+// the transport (SSM, SSH, ...) a NodeadmCredentialsProvider needs to reach
+// it for e2e teardown.
+type RemoteCommandExecutor interface {
+	RunCommands(ctx context.Context, commands []string) ([]Output, error)
+}
+
+// ssmExecutor runs commands through SSM Run Command. It requires the node to
+// be registered with the SSM agent, which is not true for every credential
+// provider (e.g. IAM Roles Anywhere nodes).
+type ssmExecutor struct {
+	client     *ssm.Client
+	instanceID string
+}
+
+func (e *ssmExecutor) RunCommands(ctx context.Context, commands []string) ([]Output, error) {
+	cfg := &ssmConfig{
+		client:     e.client,
+		instanceID: e.instanceID,
+		commands:   commands,
+	}
+	return cfg.runCommandsOnInstanceWaitForInProgress(ctx, logr.Discard())
+}
+
+// sshExecutor runs commands over SSH using a per-test keypair, for providers
+// whose nodes have no reason to run the SSM agent.
+type sshExecutor struct {
+	host   string
+	signer ssh.Signer
+}
+
+func (e *sshExecutor) RunCommands(ctx context.Context, commands []string) ([]Output, error) {
+	config := &ssh.ClientConfig{
+		User:            sshUser,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(e.signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // e2e test harness talking to a throwaway instance
+		Timeout:         sshDialTimeout,
+	}
+
+	addr := net.JoinHostPort(e.host, "22")
+	conn, err := (&net.Dialer{Timeout: sshDialTimeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s over ssh: %w", e.host, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("establishing ssh connection to %s: %w", e.host, err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	outputs := make([]Output, 0, len(commands))
+	for _, command := range commands {
+		output, err := runSSHCommand(ctx, client, command)
+		outputs = append(outputs, output)
+		if err != nil {
+			return outputs, err
+		}
+	}
+	return outputs, nil
+}
+
+func runSSHCommand(ctx context.Context, client *ssh.Client, command string) (Output, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return Output{}, fmt.Errorf("opening ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(command)
+	}()
+
+	return waitForSSHCommand(ctx, done, func() {
+		session.Signal(ssh.SIGKILL)
+		session.Close()
+	}, command, &stderr)
+}
+
+// waitForSSHCommand blocks until command finishes on done or ctx is done,
+// killing the session via cancel in the latter case. It is split out of
+// runSSHCommand so the ctx-cancellation race can be exercised without a real
+// ssh.Client.
+func waitForSSHCommand(ctx context.Context, done <-chan error, cancel func(), command string, stderr *bytes.Buffer) (Output, error) {
+	select {
+	case <-ctx.Done():
+		cancel()
+		status := "Failed"
+		return Output{Status: &status}, fmt.Errorf("running %q over ssh: %w", command, ctx.Err())
+	case err := <-done:
+		status := "Success"
+		if err != nil {
+			status = "Failed"
+			return Output{Status: &status}, fmt.Errorf("running %q over ssh: %w: %s", command, err, stderr.String())
+		}
+		return Output{Status: &status}, nil
+	}
+}
+
+// erroredExecutor is returned when a RemoteExecutor cannot be constructed
+// (e.g. keypair generation failed) so the failure surfaces from RunCommands
+// at the normal call site instead of a constructor.
+type erroredExecutor struct {
+	err error
+}
+
+func (e *erroredExecutor) RunCommands(ctx context.Context, commands []string) ([]Output, error) {
+	return nil, e.err
+}
+
+func nodeInternalIP(node corev1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			return addr.Address
+		}
+	}
+	return ""
+}
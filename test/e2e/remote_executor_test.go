@@ -0,0 +1,92 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitForSSHCommandSuccess(t *testing.T) {
+	done := make(chan error, 1)
+	done <- nil
+
+	var canceled bool
+	output, err := waitForSSHCommand(context.Background(), done, func() { canceled = true }, "true", &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("waitForSSHCommand returned error: %v", err)
+	}
+	if output.Status == nil || *output.Status != "Success" {
+		t.Fatalf("expected Success status, got %v", output.Status)
+	}
+	if canceled {
+		t.Fatal("did not expect cancel to be called on success")
+	}
+}
+
+func TestWaitForSSHCommandCommandError(t *testing.T) {
+	done := make(chan error, 1)
+	done <- errors.New("exit status 1")
+
+	var stderr bytes.Buffer
+	stderr.WriteString("command not found")
+
+	output, err := waitForSSHCommand(context.Background(), done, func() {}, "bogus", &stderr)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if output.Status == nil || *output.Status != "Failed" {
+		t.Fatalf("expected Failed status, got %v", output.Status)
+	}
+}
+
+func TestWaitForSSHCommandCtxCancellation(t *testing.T) {
+	done := make(chan error) // never sent to, simulating a hung command
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var canceled bool
+	output, err := waitForSSHCommand(ctx, done, func() { canceled = true }, "sleep 100", &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected an error from ctx cancellation")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error to wrap context.Canceled, got %v", err)
+	}
+	if output.Status == nil || *output.Status != "Failed" {
+		t.Fatalf("expected Failed status, got %v", output.Status)
+	}
+	if !canceled {
+		t.Fatal("expected cancel to be called when ctx is done")
+	}
+}
+
+func TestWaitForSSHCommandCtxDeadlineExceeded(t *testing.T) {
+	done := make(chan error)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := waitForSSHCommand(ctx, done, func() {}, "sleep 100", &bytes.Buffer{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestErroredExecutorRunCommands(t *testing.T) {
+	wantErr := errors.New("generating ssh keypair for node: boom")
+	e := &erroredExecutor{err: wantErr}
+
+	outputs, err := e.RunCommands(context.Background(), []string{"echo hi"})
+	if outputs != nil {
+		t.Fatalf("expected nil outputs, got %v", outputs)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected RunCommands to surface the stored error, got %v", err)
+	}
+}